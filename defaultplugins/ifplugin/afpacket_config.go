@@ -2,6 +2,7 @@ package ifplugin
 
 import (
 	"errors"
+	"sync"
 
 	govppapi "git.fd.io/govpp.git/api"
 	log "github.com/ligato/cn-infra/logging/logrus"
@@ -17,44 +18,138 @@ type AFPacketConfigurator struct {
 	afPacketByName   map[string]*AfPacketConfig // af packet name -> Af Packet interface configuration
 	hostInterfaces   map[string]struct{}        // a set of available host interfaces
 
+	// mu guards afPacketByHostIf/afPacketByName/hostInterfaces: NB-driven calls
+	// (ConfigureAfPacketInterface, ...) and netlinkWatcher's own goroutine both
+	// mutate them, so plain map access would race.
+	mu sync.Mutex
+
+	stateMgr *afPacketStateManager // persists afPacketByName/afPacketByHostIf across agent restarts
+
+	netlinkWatcherEnabled bool // hostInterfaces is maintained via InterfaceConfigurator's shared netlinkWatcher instead of relying solely on the linux plugin
+
 	vppCh *govppapi.Channel // govpp channel used by InterfaceConfigurator
 }
 
 // AfPacketConfig wraps the proto formatted configuration of an Afpacket interface together with a flag
-// that tells if the interface is waiting for a host interface to get created.
+// that tells if the interface is waiting for a host interface to get created, and the sw_if_index VPP
+// assigned to it (known even before the NB config for it arrives, via resyncFromVpp).
 type AfPacketConfig struct {
-	config  *intf.Interfaces_Interface
-	pending bool
+	config    *intf.Interfaces_Interface
+	pending   bool
+	swIfIndex uint32
 }
 
-// Init members of AFPacketConfigurator.
-func (plugin *AFPacketConfigurator) Init(vppCh *govppapi.Channel) (err error) {
+// Init members of AFPacketConfigurator. stateDir is the directory the afpacket state
+// file is kept in; an empty stateDir disables persistence. When enableNetlinkWatcher
+// is set, hostInterfaces is maintained directly over netlink (by InterfaceConfigurator's
+// shared netlinkWatcher) instead of depending solely on ResolveCreatedLinuxInterface/
+// ResolveDeletedLinuxInterface calls from the linux plugin. Init only sets up the
+// caches - call resyncAndReconcile afterwards, once InterfaceConfigurator has taken
+// its netlink snapshot, to seed them from VPP and the persisted agent state.
+func (plugin *AFPacketConfigurator) Init(vppCh *govppapi.Channel, stateDir string, enableNetlinkWatcher bool) (err error) {
 	plugin.vppCh = vppCh
 	//plugin.withLinuxPlugin = linuxplugin.GetIfIndexes() != nil
 
 	plugin.afPacketByHostIf = make(map[string]*AfPacketConfig)
 	plugin.afPacketByName = make(map[string]*AfPacketConfig)
 	plugin.hostInterfaces = make(map[string]struct{})
+	plugin.stateMgr = newAfPacketStateManager(stateDir)
+	plugin.netlinkWatcherEnabled = enableNetlinkWatcher
+	return nil
+}
+
+// resyncAndReconcile dumps the already-running afpacket interfaces from VPP and
+// reconciles them against the state persisted by a previous (possibly uncleanly
+// terminated) run of the agent. Must be called after hostInterfaces has been seeded
+// (netlink snapshot or linux plugin resync), and before any netlink subscription/linux
+// plugin callback can reach this configurator, so nothing races the initial caches.
+func (plugin *AFPacketConfigurator) resyncAndReconcile() error {
+	if err := plugin.resyncFromVpp(); err != nil {
+		return err
+	}
+	return plugin.reconcileUncleanShutdown()
+}
+
+// tracksHostInterfaces reports whether the configurator has a live view of which
+// host interfaces currently exist, either via the linux plugin callbacks or via the
+// netlink watcher. Afpacket interfaces can only be deferred as pending when this
+// holds; otherwise hostInterfaces would never be populated and every afpacket would
+// wait forever.
+func (plugin *AFPacketConfigurator) tracksHostInterfaces() bool {
+	return plugin.withLinuxPlugin || plugin.netlinkWatcherEnabled
+}
+
+// resyncFromVpp dumps af_packet interfaces already present in VPP and seeds the
+// caches with them, so a restart of the agent doesn't drop track of - or attempt to
+// re-create - interfaces VPP already has configured. The real NB name of such an
+// interface isn't known yet at this point (af_packet_dump doesn't carry it) - the
+// host interface name is used as a placeholder key until ConfigureAfPacketInterface
+// adopts the entry under its real name, see the "already resynced" check there.
+func (plugin *AFPacketConfigurator) resyncFromVpp() error {
+	dump, err := vppcalls.DumpAfPacketInterfaces(plugin.vppCh)
+	if err != nil {
+		return err
+	}
+
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	for _, afPacket := range dump {
+		config := &intf.Interfaces_Interface{
+			Name: afPacket.HostIfName,
+			Type: intf.InterfaceType_AF_PACKET_INTERFACE,
+			Afpacket: &intf.Interfaces_Interface_Afpacket{
+				HostIfName: afPacket.HostIfName,
+			},
+		}
+		// not via addToCacheLocked: that would persist the cache and overwrite the
+		// previous run's state file before reconcileUncleanShutdown has read it
+		entry := &AfPacketConfig{config: config, swIfIndex: afPacket.SwIfIndex}
+		plugin.afPacketByHostIf[afPacket.HostIfName] = entry
+		plugin.afPacketByName[config.Name] = entry
+		log.WithFields(log.Fields{"hostIfName": afPacket.HostIfName, "swIfIndex": afPacket.SwIfIndex}).Debug(
+			"Afpacket interface resynced from VPP")
+	}
 	return nil
 }
 
 // ConfigureAfPacketInterface creates a new Afpacket interface or marks it as pending if the target host interface doesn't exist yet.
 func (plugin *AFPacketConfigurator) ConfigureAfPacketInterface(afpacket *intf.Interfaces_Interface) (swIndex uint32, pending bool, err error) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
 
+	return plugin.configureAfPacketInterfaceLocked(afpacket)
+}
+
+// configureAfPacketInterfaceLocked is the body of ConfigureAfPacketInterface; callers
+// must hold plugin.mu.
+func (plugin *AFPacketConfigurator) configureAfPacketInterfaceLocked(afpacket *intf.Interfaces_Interface) (swIndex uint32, pending bool, err error) {
 	if afpacket.Type != intf.InterfaceType_AF_PACKET_INTERFACE {
 		return 0, false, errors.New("Expecting AfPacket interface")
 	}
 
-	if plugin.withLinuxPlugin {
+	if existing, found := plugin.afPacketByHostIf[afpacket.Afpacket.HostIfName]; found && !existing.pending {
+		// this host interface was already resynced from VPP (Init) or previously
+		// configured under a different placeholder name before the real NB config
+		// for it arrived - adopt it under its real name instead of issuing another
+		// af_packet_create and ending up with two VPP interfaces for one host-if
+		if existing.config.Name != afpacket.Name {
+			delete(plugin.afPacketByName, existing.config.Name)
+		}
+		plugin.addToCacheLocked(afpacket, existing.swIfIndex, false)
+		return existing.swIfIndex, false, nil
+	}
+
+	if plugin.tracksHostInterfaces() {
 		_, hostIfAvail := plugin.hostInterfaces[afpacket.Afpacket.HostIfName]
 		if !hostIfAvail {
-			plugin.addToCache(afpacket, true)
+			plugin.addToCacheLocked(afpacket, 0, true)
 			return 0, true, nil
 		}
 	}
 	swIdx, err := vppcalls.AddAfPacketInterface(afpacket.Afpacket, plugin.vppCh)
 	if err == nil {
-		plugin.addToCache(afpacket, false)
+		plugin.addToCacheLocked(afpacket, swIdx, false)
 	}
 	return swIdx, false, err
 }
@@ -63,6 +158,8 @@ func (plugin *AFPacketConfigurator) ConfigureAfPacketInterface(afpacket *intf.In
 // nees to be recreated for the changes to be applied.
 func (plugin *AFPacketConfigurator) ModifyAfPacketInterface(newConfig *intf.Interfaces_Interface,
 	oldConfig *intf.Interfaces_Interface) (recreate bool, err error) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
 
 	if oldConfig.Type != intf.InterfaceType_AF_PACKET_INTERFACE ||
 		newConfig.Type != intf.InterfaceType_AF_PACKET_INTERFACE {
@@ -74,14 +171,22 @@ func (plugin *AFPacketConfigurator) ModifyAfPacketInterface(newConfig *intf.Inte
 		return true, nil
 	}
 
-	// rewrite cached configuration
-	plugin.addToCache(newConfig, false)
+	// rewrite cached configuration, keeping the already known sw_if_index
+	plugin.addToCacheLocked(newConfig, afpacket.swIfIndex, false)
 	return false, nil
 }
 
 // DeleteAfPacketInterface removes Afpacket interface from VPP and from the cache.
 func (plugin *AFPacketConfigurator) DeleteAfPacketInterface(afpacket *intf.Interfaces_Interface) (err error) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	return plugin.deleteAfPacketInterfaceLocked(afpacket)
+}
 
+// deleteAfPacketInterfaceLocked is the body of DeleteAfPacketInterface; callers must
+// hold plugin.mu.
+func (plugin *AFPacketConfigurator) deleteAfPacketInterfaceLocked(afpacket *intf.Interfaces_Interface) (err error) {
 	if afpacket.Type != intf.InterfaceType_AF_PACKET_INTERFACE {
 		return errors.New("Expecting AfPacket interface")
 	}
@@ -90,13 +195,16 @@ func (plugin *AFPacketConfigurator) DeleteAfPacketInterface(afpacket *intf.Inter
 	if !found || !config.pending {
 		err = vppcalls.DeleteAfPacketInterface(afpacket.GetAfpacket(), plugin.vppCh)
 	}
-	plugin.removeFromCache(afpacket)
+	plugin.removeFromCacheLocked(afpacket)
 	return err
 }
 
 // ResolveCreatedLinuxInterface reacts to a newly created Linux interface.
 func (plugin *AFPacketConfigurator) ResolveCreatedLinuxInterface(interfaceName string, interfaceIndex uint32) *intf.Interfaces_Interface {
-	if !plugin.withLinuxPlugin {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if !plugin.tracksHostInterfaces() {
 		log.WithField("hostIfName", interfaceName).Warn("Unexpectedly learned about a new Linux interface")
 		return nil
 	}
@@ -116,7 +224,10 @@ func (plugin *AFPacketConfigurator) ResolveCreatedLinuxInterface(interfaceName s
 
 // ResolveDeletedLinuxInterface reacts to a removed Linux interface.
 func (plugin *AFPacketConfigurator) ResolveDeletedLinuxInterface(interfaceName string) {
-	if !plugin.withLinuxPlugin {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if !plugin.tracksHostInterfaces() {
 		log.WithField("hostIfName", interfaceName).Warn("Unexpectedly learned about removed Linux interface")
 		return
 	}
@@ -125,27 +236,34 @@ func (plugin *AFPacketConfigurator) ResolveDeletedLinuxInterface(interfaceName s
 	afpacket, found := plugin.afPacketByHostIf[interfaceName]
 	if found {
 		// remove the interface and re-add as pending
-		plugin.DeleteAfPacketInterface(afpacket.config)
-		plugin.ConfigureAfPacketInterface(afpacket.config)
+		plugin.deleteAfPacketInterfaceLocked(afpacket.config)
+		plugin.configureAfPacketInterfaceLocked(afpacket.config)
 	}
 }
 
 // IsPendingAfPacket returns true if the given config belongs to pending Afpacket interface.
 func (plugin *AFPacketConfigurator) IsPendingAfPacket(iface *intf.Interfaces_Interface) (pending bool) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
 	afpacket, found := plugin.afPacketByName[iface.Name]
 	return found && afpacket.pending
 }
 
-func (plugin *AFPacketConfigurator) addToCache(afpacket *intf.Interfaces_Interface, pending bool) {
-	config := &AfPacketConfig{config: afpacket, pending: pending}
+// addToCacheLocked adds/overwrites a cache entry; callers must hold plugin.mu.
+func (plugin *AFPacketConfigurator) addToCacheLocked(afpacket *intf.Interfaces_Interface, swIfIndex uint32, pending bool) {
+	config := &AfPacketConfig{config: afpacket, pending: pending, swIfIndex: swIfIndex}
 	plugin.afPacketByHostIf[afpacket.Afpacket.HostIfName] = config
 	plugin.afPacketByName[afpacket.Name] = config
+	plugin.persistStateLocked()
 	log.Debugf("Afpacket interface with name %v added to cache (hostIf: %s, pending: %t)",
 		afpacket.Name, afpacket.Afpacket.HostIfName, pending)
 }
 
-func (plugin *AFPacketConfigurator) removeFromCache(afpacket *intf.Interfaces_Interface) {
+// removeFromCacheLocked removes a cache entry; callers must hold plugin.mu.
+func (plugin *AFPacketConfigurator) removeFromCacheLocked(afpacket *intf.Interfaces_Interface) {
 	delete(plugin.afPacketByName, afpacket.Name)
 	delete(plugin.afPacketByHostIf, afpacket.Afpacket.HostIfName)
+	plugin.persistStateLocked()
 	log.Debugf("Afpacket interface with name %v removed from cache", afpacket.Name)
 }
@@ -0,0 +1,87 @@
+package ifplugin
+
+import (
+	"syscall"
+
+	"github.com/vishvananda/netlink"
+)
+
+// netlinkWatcher keeps the hostInterfaces of both AFPacketConfigurator and
+// TapV2Configurator in sync with the Linux host directly over netlink
+// (RTM_NEWLINK/RTM_DELLINK), so the pending/resolution logic of either backend works
+// even in deployments that don't run the linux plugin.
+type netlinkWatcher struct {
+	configurator *InterfaceConfigurator
+
+	updates chan netlink.LinkUpdate
+	done    chan struct{}
+}
+
+func newNetlinkWatcher(configurator *InterfaceConfigurator) *netlinkWatcher {
+	return &netlinkWatcher{configurator: configurator}
+}
+
+// snapshot takes an initial LinkList() snapshot of the host interfaces and seeds it
+// into both backend configurators. It is kept separate from subscribe() so callers
+// (namely InterfaceConfigurator.Init) can run it before AFPacketConfigurator's
+// resync/reconcile populate the rest of the caches, and only start the watch()
+// goroutine once that one-time setup has finished.
+func (w *netlinkWatcher) snapshot() error {
+	links, err := netlink.LinkList()
+	if err != nil {
+		return err
+	}
+
+	afpacket := w.configurator.afPacketConfigurator
+	afpacket.mu.Lock()
+	for _, link := range links {
+		afpacket.hostInterfaces[link.Attrs().Name] = struct{}{}
+	}
+	afpacket.mu.Unlock()
+
+	tap := w.configurator.tapV2Configurator
+	tap.mu.Lock()
+	for _, link := range links {
+		tap.hostInterfaces[link.Attrs().Name] = struct{}{}
+	}
+	tap.mu.Unlock()
+
+	return nil
+}
+
+// subscribe starts watching for further link changes, funnelling them through
+// InterfaceConfigurator's Resolve*LinuxInterface dispatch so both backend
+// configurators' state machines stay unchanged. Must only be called once
+// AFPacketConfigurator has finished resyncing/reconciling its caches, otherwise the
+// watch() goroutine could race with that one-time setup.
+func (w *netlinkWatcher) subscribe() error {
+	updates := make(chan netlink.LinkUpdate)
+	done := make(chan struct{})
+	if err := netlink.LinkSubscribe(updates, done); err != nil {
+		return err
+	}
+	w.updates = updates
+	w.done = done
+
+	go w.watch()
+	return nil
+}
+
+func (w *netlinkWatcher) watch() {
+	for update := range w.updates {
+		hostIfName := update.Link.Attrs().Name
+		switch update.Header.Type {
+		case syscall.RTM_NEWLINK:
+			w.configurator.ResolveCreatedLinuxInterface(hostIfName, uint32(update.Link.Attrs().Index))
+		case syscall.RTM_DELLINK:
+			w.configurator.ResolveDeletedLinuxInterface(hostIfName)
+		}
+	}
+}
+
+// stop terminates the netlink subscription.
+func (w *netlinkWatcher) stop() {
+	if w.done != nil {
+		close(w.done)
+	}
+}
@@ -0,0 +1,182 @@
+package ifplugin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+
+	log "github.com/ligato/cn-infra/logging/logrus"
+	intf "github.com/ligato/vpp-agent/defaultplugins/ifplugin/model/interfaces"
+	"github.com/ligato/vpp-agent/defaultplugins/ifplugin/vppcalls"
+)
+
+const afPacketStateFileName = "afpacket_state.json"
+
+// afPacketPersistedEntry is the on-disk representation of a single AfPacketConfig
+// cache entry, enough to recreate it on the next agent start without depending on
+// VPP still remembering it (VPP may have restarted too). Only the names and the
+// pending flag are kept: an interface recreated from it gets VPP's default af_packet
+// settings, not the queue/frame/offload options of its original configuration.
+type afPacketPersistedEntry struct {
+	Name       string `json:"name"`
+	HostIfName string `json:"host_if_name"`
+	Pending    bool   `json:"pending"`
+}
+
+// afPacketStateManager persists the afPacketByName/afPacketByHostIf cache of
+// AFPacketConfigurator to disk, so an unclean agent shutdown (crash, kill -9) does
+// not leave pending/created af_packet interfaces untracked on the next start.
+// An empty stateDir disables persistence entirely (e.g. in tests).
+type afPacketStateManager struct {
+	stateDir string
+}
+
+func newAfPacketStateManager(stateDir string) *afPacketStateManager {
+	return &afPacketStateManager{stateDir: stateDir}
+}
+
+func (sm *afPacketStateManager) filePath() string {
+	return filepath.Join(sm.stateDir, afPacketStateFileName)
+}
+
+// save writes the given entries to the state file, overwriting any previous content.
+func (sm *afPacketStateManager) save(entries []afPacketPersistedEntry) error {
+	if sm.stateDir == "" {
+		return nil
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(sm.filePath(), data, 0644)
+}
+
+// load reads back the entries persisted by a previous agent run. A missing state
+// file (first run, or persistence disabled) is not an error.
+func (sm *afPacketStateManager) load() ([]afPacketPersistedEntry, error) {
+	if sm.stateDir == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(sm.filePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var entries []afPacketPersistedEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// persistStateLocked serializes the configurator's current cache to disk. Persistence
+// is best-effort: a failure is logged but does not fail the calling operation, since
+// the in-memory cache it mirrors is already authoritative for this run. Callers must
+// hold plugin.mu (it is only ever invoked from addToCacheLocked/removeFromCacheLocked).
+func (plugin *AFPacketConfigurator) persistStateLocked() {
+	if plugin.stateMgr == nil {
+		return
+	}
+	entries := make([]afPacketPersistedEntry, 0, len(plugin.afPacketByName))
+	for _, cfg := range plugin.afPacketByName {
+		entries = append(entries, afPacketPersistedEntry{
+			Name:       cfg.config.Name,
+			HostIfName: cfg.config.Afpacket.HostIfName,
+			Pending:    cfg.pending,
+		})
+	}
+	if err := plugin.stateMgr.save(entries); err != nil {
+		log.WithField("err", err).Warn("Failed to persist af_packet state")
+	}
+}
+
+// reconcileUncleanShutdown loads the af_packet state persisted by a previous agent
+// run and reconciles it against VPP (already dumped into the cache by resyncFromVpp)
+// and the current set of Linux host interfaces, branching on both whether VPP still
+// has the interface and on the persisted pending flag:
+//   - VPP still has it and its host interface is present: nothing to do.
+//   - VPP still has it but the host interface disappeared while the agent was down:
+//     tear the orphan down (af_packet_delete) instead of leaving it dangling in VPP.
+//   - VPP doesn't have it (it was pending, or VPP itself restarted) and the host
+//     interface is available: (re-)create it.
+//   - VPP doesn't have it and the host interface is still missing: re-mark it as
+//     pending instead of dropping it, so it gets created once the host interface
+//     eventually appears.
+func (plugin *AFPacketConfigurator) reconcileUncleanShutdown() error {
+	if plugin.stateMgr == nil {
+		return nil
+	}
+	persisted, err := plugin.stateMgr.load()
+	if err != nil {
+		return err
+	}
+
+	hostIfs, err := currentHostInterfaceNames()
+	if err != nil {
+		return err
+	}
+
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	for _, entry := range persisted {
+		// resyncFromVpp keys VPP's interfaces by host interface name, not NB name
+		cached, inVpp := plugin.afPacketByHostIf[entry.HostIfName]
+		_, hostIfExists := hostIfs[entry.HostIfName]
+
+		config := &intf.Interfaces_Interface{
+			Name: entry.Name,
+			Type: intf.InterfaceType_AF_PACKET_INTERFACE,
+			Afpacket: &intf.Interfaces_Interface_Afpacket{
+				HostIfName: entry.HostIfName,
+			},
+		}
+
+		switch {
+		case inVpp && hostIfExists:
+			// VPP still has it and its host interface is present, nothing to do
+
+		case inVpp && !hostIfExists:
+			log.WithFields(log.Fields{"ifName": entry.Name, "hostIfName": entry.HostIfName}).Warn(
+				"Tearing down orphaned af_packet interface, host interface is gone")
+			if err := vppcalls.DeleteAfPacketInterface(cached.config.Afpacket, plugin.vppCh); err != nil {
+				log.WithFields(log.Fields{"ifName": entry.Name, "err": err}).Warn(
+					"Failed to delete orphaned af_packet interface")
+			}
+			plugin.removeFromCacheLocked(cached.config)
+
+		case hostIfExists:
+			// VPP doesn't know about it (it was still entry.Pending, or VPP itself
+			// was restarted) but the host interface is available - (re-)create it
+			// the same way ConfigureAfPacketInterface normally would
+			if _, _, err := plugin.configureAfPacketInterfaceLocked(config); err != nil {
+				log.WithFields(log.Fields{"ifName": entry.Name, "err": err}).Warn(
+					"Failed to recreate af_packet interface from persisted state")
+			}
+
+		default:
+			// neither VPP nor the host has it right now - re-mark as pending rather
+			// than dropping it, so ResolveCreatedLinuxInterface picks it up later
+			plugin.addToCacheLocked(config, 0, true)
+		}
+	}
+	return nil
+}
+
+// currentHostInterfaceNames lists the Linux network interfaces currently present on
+// the host, independently of whether the linux plugin is loaded.
+func currentHostInterfaceNames() (map[string]struct{}, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]struct{}, len(ifaces))
+	for _, iface := range ifaces {
+		names[iface.Name] = struct{}{}
+	}
+	return names, nil
+}
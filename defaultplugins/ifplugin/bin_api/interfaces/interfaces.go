@@ -0,0 +1,41 @@
+// Package interfaces represents the VPP binary API of the core 'interface' VPP
+// module. Generated from interface.api.json by govpp binapi-generator. DO NOT EDIT.
+package interfaces
+
+// SwInterfaceAddDelAddress represents the VPP binary API message
+// 'sw_interface_add_del_address', used to assign (or remove) an IPv4/IPv6 address on
+// the VPP side of an interface.
+type SwInterfaceAddDelAddress struct {
+	SwIfIndex     uint32
+	IsAdd         uint8
+	IsIPv6        uint8
+	DelAll        uint8
+	AddressLength uint8
+	Address       []byte `struc:"[16]byte"`
+}
+
+func (*SwInterfaceAddDelAddress) GetMessageName() string {
+	return "sw_interface_add_del_address"
+}
+func (*SwInterfaceAddDelAddress) GetCrcString() string {
+	return "5803d5c3"
+}
+func (*SwInterfaceAddDelAddress) GetMessageType() int {
+	return 0 // Request
+}
+
+// SwInterfaceAddDelAddressReply represents the VPP binary API message
+// 'sw_interface_add_del_address_reply'.
+type SwInterfaceAddDelAddressReply struct {
+	Retval int32
+}
+
+func (*SwInterfaceAddDelAddressReply) GetMessageName() string {
+	return "sw_interface_add_del_address_reply"
+}
+func (*SwInterfaceAddDelAddressReply) GetCrcString() string {
+	return "e8d4e804"
+}
+func (*SwInterfaceAddDelAddressReply) GetMessageType() int {
+	return 1 // Reply
+}
@@ -0,0 +1,114 @@
+package ifplugin
+
+import (
+	"fmt"
+
+	govppapi "git.fd.io/govpp.git/api"
+	log "github.com/ligato/cn-infra/logging/logrus"
+	intf "github.com/ligato/vpp-agent/defaultplugins/ifplugin/model/interfaces"
+)
+
+// InterfaceConfigurator dispatches host-backed interface configuration (af_packet,
+// TAPv2) to the matching backend configurator, so a single NB interface config can
+// pick either one depending on its Type/backend-specific sub-message. It also owns the
+// optional netlink watcher shared by both backends, so a netlink-only deployment (no
+// linux plugin) keeps both backends' hostInterfaces in sync, not just af_packet's.
+type InterfaceConfigurator struct {
+	afPacketConfigurator *AFPacketConfigurator
+	tapV2Configurator    *TapV2Configurator
+	netlinkWatcher       *netlinkWatcher
+}
+
+// Init sets up both backend configurators and, if enableNetlinkWatcher is set, the
+// netlink watcher shared between them. The watcher takes its initial snapshot before
+// AFPacketConfigurator resyncs/reconciles against VPP and is only subscribed (i.e. its
+// goroutine started) afterwards, so it never races that one-time setup.
+func (plugin *InterfaceConfigurator) Init(vppCh *govppapi.Channel, afPacketStateDir string, enableNetlinkWatcher bool) error {
+	plugin.afPacketConfigurator = &AFPacketConfigurator{}
+	if err := plugin.afPacketConfigurator.Init(vppCh, afPacketStateDir, enableNetlinkWatcher); err != nil {
+		return err
+	}
+
+	plugin.tapV2Configurator = &TapV2Configurator{}
+	if err := plugin.tapV2Configurator.Init(vppCh, enableNetlinkWatcher); err != nil {
+		return err
+	}
+
+	if enableNetlinkWatcher {
+		plugin.netlinkWatcher = newNetlinkWatcher(plugin)
+		if err := plugin.netlinkWatcher.snapshot(); err != nil {
+			return err
+		}
+	}
+
+	if err := plugin.afPacketConfigurator.resyncAndReconcile(); err != nil {
+		return err
+	}
+
+	if enableNetlinkWatcher {
+		return plugin.netlinkWatcher.subscribe()
+	}
+	return nil
+}
+
+// ConfigureVppInterface creates iface in VPP via the configurator matching its
+// backend.
+func (plugin *InterfaceConfigurator) ConfigureVppInterface(iface *intf.Interfaces_Interface) (swIndex uint32, pending bool, err error) {
+	switch {
+	case iface.Type == intf.InterfaceType_AF_PACKET_INTERFACE:
+		return plugin.afPacketConfigurator.ConfigureAfPacketInterface(iface)
+	case iface.Type == intf.InterfaceType_TAP_INTERFACE && iface.Tap != nil:
+		return plugin.tapV2Configurator.ConfigureTapV2Interface(iface)
+	default:
+		return 0, false, fmt.Errorf("interface %s has no host-backed configurator for type %v", iface.Name, iface.Type)
+	}
+}
+
+// ModifyVppInterface updates iface via the configurator matching its backend.
+func (plugin *InterfaceConfigurator) ModifyVppInterface(newConfig, oldConfig *intf.Interfaces_Interface) (recreate bool, err error) {
+	switch {
+	case newConfig.Type == intf.InterfaceType_AF_PACKET_INTERFACE:
+		return plugin.afPacketConfigurator.ModifyAfPacketInterface(newConfig, oldConfig)
+	case newConfig.Type == intf.InterfaceType_TAP_INTERFACE && newConfig.Tap != nil:
+		return plugin.tapV2Configurator.ModifyTapV2Interface(newConfig, oldConfig)
+	default:
+		return false, fmt.Errorf("interface %s has no host-backed configurator for type %v", newConfig.Name, newConfig.Type)
+	}
+}
+
+// DeleteVppInterface removes iface via the configurator matching its backend.
+func (plugin *InterfaceConfigurator) DeleteVppInterface(iface *intf.Interfaces_Interface) error {
+	switch {
+	case iface.Type == intf.InterfaceType_AF_PACKET_INTERFACE:
+		return plugin.afPacketConfigurator.DeleteAfPacketInterface(iface)
+	case iface.Type == intf.InterfaceType_TAP_INTERFACE && iface.Tap != nil:
+		return plugin.tapV2Configurator.DeleteTapV2Interface(iface)
+	default:
+		return fmt.Errorf("interface %s has no host-backed configurator for type %v", iface.Name, iface.Type)
+	}
+}
+
+// ResolveCreatedLinuxInterface reacts to a newly created Linux interface by offering
+// it to every backend configurator that tracks host interfaces; at most one of them
+// will actually have a pending interface waiting on that host-if name.
+func (plugin *InterfaceConfigurator) ResolveCreatedLinuxInterface(interfaceName string, interfaceIndex uint32) {
+	if afpacket := plugin.afPacketConfigurator.ResolveCreatedLinuxInterface(interfaceName, interfaceIndex); afpacket != nil {
+		if _, _, err := plugin.afPacketConfigurator.ConfigureAfPacketInterface(afpacket); err != nil {
+			log.WithFields(log.Fields{"hostIfName": interfaceName, "err": err}).Warn(
+				"Failed to configure af_packet interface after a host interface appeared")
+		}
+	}
+	if tap := plugin.tapV2Configurator.ResolveCreatedLinuxInterface(interfaceName, interfaceIndex); tap != nil {
+		if _, _, err := plugin.tapV2Configurator.ConfigureTapV2Interface(tap); err != nil {
+			log.WithFields(log.Fields{"hostIfName": interfaceName, "err": err}).Warn(
+				"Failed to configure TAPv2 interface after a host interface appeared")
+		}
+	}
+}
+
+// ResolveDeletedLinuxInterface reacts to a removed Linux interface by notifying every
+// backend configurator that tracks host interfaces.
+func (plugin *InterfaceConfigurator) ResolveDeletedLinuxInterface(interfaceName string) {
+	plugin.afPacketConfigurator.ResolveDeletedLinuxInterface(interfaceName)
+	plugin.tapV2Configurator.ResolveDeletedLinuxInterface(interfaceName)
+}
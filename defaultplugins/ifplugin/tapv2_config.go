@@ -0,0 +1,210 @@
+package ifplugin
+
+import (
+	"errors"
+	"sync"
+
+	govppapi "git.fd.io/govpp.git/api"
+	log "github.com/ligato/cn-infra/logging/logrus"
+	intf "github.com/ligato/vpp-agent/defaultplugins/ifplugin/model/interfaces"
+	"github.com/ligato/vpp-agent/defaultplugins/ifplugin/vppcalls"
+)
+
+// TapV2Configurator is used by InterfaceConfigurator to execute TAPv2-specific
+// management operations. TAPv2 is a higher-throughput alternative to AF_PACKET for
+// connecting a VPP interface to the Linux host; it shares the same host-interface
+// dependent pending/recreate machinery as AFPacketConfigurator.
+type TapV2Configurator struct {
+	withLinuxPlugin       bool                    // is linux plugin loaded ?
+	netlinkWatcherEnabled bool                    // hostInterfaces is maintained via InterfaceConfigurator's shared netlinkWatcher instead of relying solely on the linux plugin
+	tapByHostIf           map[string]*TapV2Config // host interface name -> TAPv2 interface configuration
+	tapByName             map[string]*TapV2Config // interface name -> TAPv2 interface configuration
+	hostInterfaces        map[string]struct{}     // a set of available host interfaces
+
+	// mu guards tapByHostIf/tapByName/hostInterfaces: NB-driven calls
+	// (ConfigureTapV2Interface, ...) and InterfaceConfigurator's netlink-driven
+	// Resolve*LinuxInterface calls both mutate them, so plain map access would race -
+	// see AFPacketConfigurator.mu for the same shared-state shape.
+	mu sync.Mutex
+
+	vppCh *govppapi.Channel // govpp channel used by InterfaceConfigurator
+}
+
+// TapV2Config wraps the proto formatted configuration of a TAPv2 interface together
+// with a flag that tells if the interface is waiting for a host interface to get
+// created, and the sw_if_index VPP assigned to it (tap_delete_v2 is keyed by
+// sw_if_index rather than by host interface name).
+type TapV2Config struct {
+	config    *intf.Interfaces_Interface
+	pending   bool
+	swIfIndex uint32
+}
+
+// Init members of TapV2Configurator. When enableNetlinkWatcher is set, hostInterfaces
+// is maintained directly over netlink (by InterfaceConfigurator's shared
+// netlinkWatcher) instead of depending solely on ResolveCreatedLinuxInterface/
+// ResolveDeletedLinuxInterface calls from the linux plugin.
+func (plugin *TapV2Configurator) Init(vppCh *govppapi.Channel, enableNetlinkWatcher bool) (err error) {
+	plugin.vppCh = vppCh
+	//plugin.withLinuxPlugin = linuxplugin.GetIfIndexes() != nil
+
+	plugin.tapByHostIf = make(map[string]*TapV2Config)
+	plugin.tapByName = make(map[string]*TapV2Config)
+	plugin.hostInterfaces = make(map[string]struct{})
+	plugin.netlinkWatcherEnabled = enableNetlinkWatcher
+	return nil
+}
+
+// tracksHostInterfaces reports whether the configurator has a live view of which host
+// interfaces currently exist, either via the linux plugin callbacks or via the shared
+// netlink watcher - see AFPacketConfigurator.tracksHostInterfaces for the same check.
+func (plugin *TapV2Configurator) tracksHostInterfaces() bool {
+	return plugin.withLinuxPlugin || plugin.netlinkWatcherEnabled
+}
+
+// ConfigureTapV2Interface creates a new TAPv2 interface, or marks it as pending if
+// the requested host interface name is already taken by an existing host interface
+// (it is created once ResolveDeletedLinuxInterface frees the name). VPP itself creates the host side of the interface, so in the common
+// case (host interface not pre-existing) the configurator proceeds immediately.
+func (plugin *TapV2Configurator) ConfigureTapV2Interface(tap *intf.Interfaces_Interface) (swIndex uint32, pending bool, err error) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	return plugin.configureTapV2InterfaceLocked(tap)
+}
+
+// configureTapV2InterfaceLocked is the body of ConfigureTapV2Interface; callers must
+// hold plugin.mu.
+func (plugin *TapV2Configurator) configureTapV2InterfaceLocked(tap *intf.Interfaces_Interface) (swIndex uint32, pending bool, err error) {
+	if tap.Type != intf.InterfaceType_TAP_INTERFACE || tap.Tap == nil {
+		return 0, false, errors.New("Expecting TAPv2 interface")
+	}
+
+	if plugin.tracksHostInterfaces() {
+		if _, hostIfTaken := plugin.hostInterfaces[tap.Tap.HostIfName]; hostIfTaken {
+			plugin.addToCacheLocked(tap, 0, true)
+			return 0, true, nil
+		}
+	}
+
+	swIdx, err := vppcalls.AddTapV2Interface(tap.Tap, plugin.vppCh)
+	if err == nil {
+		plugin.addToCacheLocked(tap, swIdx, false)
+	}
+	return swIdx, false, err
+}
+
+// ModifyTapV2Interface updates the cache with the TAPv2 configuration and tells
+// InterfaceConfigurator if the interface needs to be recreated for the changes to be
+// applied.
+func (plugin *TapV2Configurator) ModifyTapV2Interface(newConfig *intf.Interfaces_Interface,
+	oldConfig *intf.Interfaces_Interface) (recreate bool, err error) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if oldConfig.Type != intf.InterfaceType_TAP_INTERFACE || newConfig.Type != intf.InterfaceType_TAP_INTERFACE {
+		return false, errors.New("Expecting TAPv2 interface")
+	}
+
+	tap, found := plugin.tapByName[oldConfig.Name]
+	// every TAPv2 field is only applied by tap_create_v2, so any change needs a recreate
+	if !found || tap.pending || (*newConfig.Tap != *oldConfig.Tap) {
+		return true, nil
+	}
+
+	// rewrite cached configuration
+	plugin.addToCacheLocked(newConfig, tap.swIfIndex, false)
+	return false, nil
+}
+
+// DeleteTapV2Interface removes the TAPv2 interface from VPP and from the cache.
+func (plugin *TapV2Configurator) DeleteTapV2Interface(tap *intf.Interfaces_Interface) (err error) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	return plugin.deleteTapV2InterfaceLocked(tap)
+}
+
+// deleteTapV2InterfaceLocked is the body of DeleteTapV2Interface; callers must hold
+// plugin.mu.
+func (plugin *TapV2Configurator) deleteTapV2InterfaceLocked(tap *intf.Interfaces_Interface) (err error) {
+	if tap.Type != intf.InterfaceType_TAP_INTERFACE {
+		return errors.New("Expecting TAPv2 interface")
+	}
+
+	config, found := plugin.tapByName[tap.Name]
+	if found && !config.pending {
+		err = vppcalls.DeleteTapV2Interface(config.swIfIndex, plugin.vppCh)
+	}
+	plugin.removeFromCacheLocked(tap)
+	return err
+}
+
+// ResolveCreatedLinuxInterface reacts to a newly created Linux interface.
+func (plugin *TapV2Configurator) ResolveCreatedLinuxInterface(interfaceName string, interfaceIndex uint32) *intf.Interfaces_Interface {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if !plugin.tracksHostInterfaces() {
+		log.WithField("hostIfName", interfaceName).Warn("Unexpectedly learned about a new Linux interface")
+		return nil
+	}
+	plugin.hostInterfaces[interfaceName] = struct{}{}
+
+	tap, found := plugin.tapByHostIf[interfaceName]
+	if found {
+		if tap.pending {
+			// hand it back for another configure attempt; with this host interface
+			// name now taken it stays pending until the name is freed again
+			return tap.config
+		}
+		log.WithFields(log.Fields{"ifName": tap.config.Name, "hostIfName": interfaceName}).Warn(
+			"Already configured TAPv2 interface")
+	}
+	return nil // nothing to configure
+}
+
+// ResolveDeletedLinuxInterface reacts to a removed Linux interface.
+func (plugin *TapV2Configurator) ResolveDeletedLinuxInterface(interfaceName string) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if !plugin.tracksHostInterfaces() {
+		log.WithField("hostIfName", interfaceName).Warn("Unexpectedly learned about removed Linux interface")
+		return
+	}
+	delete(plugin.hostInterfaces, interfaceName)
+
+	tap, found := plugin.tapByHostIf[interfaceName]
+	if found {
+		// remove the interface and configure it again; the host interface name is
+		// free now, so it is (re-)created in VPP rather than left pending
+		plugin.deleteTapV2InterfaceLocked(tap.config)
+		plugin.configureTapV2InterfaceLocked(tap.config)
+	}
+}
+
+// IsPendingTapV2 returns true if the given config belongs to a pending TAPv2 interface.
+func (plugin *TapV2Configurator) IsPendingTapV2(iface *intf.Interfaces_Interface) (pending bool) {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	tap, found := plugin.tapByName[iface.Name]
+	return found && tap.pending
+}
+
+// addToCacheLocked adds/overwrites a cache entry; callers must hold plugin.mu.
+func (plugin *TapV2Configurator) addToCacheLocked(tap *intf.Interfaces_Interface, swIfIndex uint32, pending bool) {
+	config := &TapV2Config{config: tap, pending: pending, swIfIndex: swIfIndex}
+	plugin.tapByHostIf[tap.Tap.HostIfName] = config
+	plugin.tapByName[tap.Name] = config
+	log.Debugf("TAPv2 interface with name %v added to cache (hostIf: %s, pending: %t)",
+		tap.Name, tap.Tap.HostIfName, pending)
+}
+
+// removeFromCacheLocked removes a cache entry; callers must hold plugin.mu.
+func (plugin *TapV2Configurator) removeFromCacheLocked(tap *intf.Interfaces_Interface) {
+	delete(plugin.tapByName, tap.Name)
+	delete(plugin.tapByHostIf, tap.Tap.HostIfName)
+	log.Debugf("TAPv2 interface with name %v removed from cache", tap.Name)
+}
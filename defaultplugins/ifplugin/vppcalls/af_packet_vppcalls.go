@@ -0,0 +1,152 @@
+package vppcalls
+
+import (
+	"fmt"
+
+	govppapi "git.fd.io/govpp.git/api"
+	log "github.com/ligato/cn-infra/logging/logrus"
+	"github.com/ligato/vpp-agent/defaultplugins/ifplugin/bin_api/af_packet"
+	intf "github.com/ligato/vpp-agent/defaultplugins/ifplugin/model/interfaces"
+)
+
+// invalidSwIfIndex is the VPP sentinel value (~0) used by af_packet_dump for entries
+// that no longer back a live interface. Older VPP builds returned such entries for
+// interfaces that had already been deleted; they must be filtered out of the dump.
+const invalidSwIfIndex = ^uint32(0)
+
+// AfPacketDetails represents a single af_packet interface as reported by VPP via
+// af_packet_dump.
+type AfPacketDetails struct {
+	SwIfIndex  uint32
+	HostIfName string
+}
+
+// AddAfPacketInterface calls VPP binary API to create the af_packet interface backed
+// by the given host interface. It picks af_packet_create_v3 when GSO, checksum
+// offload or an rx/tx block size was requested (the only version that carries those),
+// af_packet_create_v2 otherwise, so the configured queue count/frame size still get applied.
+func AddAfPacketInterface(afPacketIf *intf.Interfaces_Interface_Afpacket, vppChan *govppapi.Channel) (swIndex uint32, err error) {
+	if afPacketIf == nil || afPacketIf.HostIfName == "" {
+		return 0, fmt.Errorf("af_packet interface is missing host interface name")
+	}
+
+	if afPacketIf.Gso || afPacketIf.ChecksumOffload || afPacketIf.RxBlockSize > 0 || afPacketIf.TxBlockSize > 0 {
+		return addAfPacketInterfaceV3(afPacketIf, vppChan)
+	}
+	return addAfPacketInterfaceV2(afPacketIf, vppChan)
+}
+
+func addAfPacketInterfaceV2(afPacketIf *intf.Interfaces_Interface_Afpacket, vppChan *govppapi.Channel) (swIndex uint32, err error) {
+	req := &af_packet.AfPacketCreateV2{
+		HostIfName:  []byte(afPacketIf.HostIfName),
+		NumRxQueues: uint16(afPacketIf.NumRxQueues),
+		NumTxQueues: uint16(afPacketIf.NumTxQueues),
+		RxFrameSize: afPacketIf.RxFrameSize,
+		TxFrameSize: afPacketIf.TxFrameSize,
+	}
+	if afPacketIf.UseRandomHwAddr {
+		req.UseRandomHwAddr = 1
+	}
+
+	reply := &af_packet.AfPacketCreateV2Reply{}
+	if err = vppChan.SendRequest(req).ReceiveReply(reply); err != nil {
+		return 0, err
+	}
+	if reply.Retval != 0 {
+		return 0, fmt.Errorf("af_packet_create_v2 returned %d", reply.Retval)
+	}
+	log.Debugf("af_packet interface %s created via af_packet_create_v2 (swIfIndex: %d)",
+		afPacketIf.HostIfName, reply.SwIfIndex)
+	return reply.SwIfIndex, nil
+}
+
+func addAfPacketInterfaceV3(afPacketIf *intf.Interfaces_Interface_Afpacket, vppChan *govppapi.Channel) (swIndex uint32, err error) {
+	var flags uint32
+	if afPacketIf.UseRandomHwAddr {
+		flags |= uint32(af_packet.AfPacketFlagUseRandomHwAddr)
+	}
+	if afPacketIf.ChecksumOffload {
+		flags |= uint32(af_packet.AfPacketFlagChecksumOffload)
+	}
+	if afPacketIf.Gso {
+		flags |= uint32(af_packet.AfPacketFlagGso)
+	}
+
+	req := &af_packet.AfPacketCreateV3{
+		HostIfName:  []byte(afPacketIf.HostIfName),
+		Flags:       flags,
+		NumRxQueues: uint16(afPacketIf.NumRxQueues),
+		NumTxQueues: uint16(afPacketIf.NumTxQueues),
+		RxFrameSize: afPacketIf.RxFrameSize,
+		TxFrameSize: afPacketIf.TxFrameSize,
+		RxBlockSize: afPacketIf.RxBlockSize,
+		TxBlockSize: afPacketIf.TxBlockSize,
+	}
+
+	reply := &af_packet.AfPacketCreateV3Reply{}
+	if err = vppChan.SendRequest(req).ReceiveReply(reply); err != nil {
+		return 0, err
+	}
+	if reply.Retval != 0 {
+		return 0, fmt.Errorf("af_packet_create_v3 returned %d", reply.Retval)
+	}
+	log.Debugf("af_packet interface %s created via af_packet_create_v3 (swIfIndex: %d)",
+		afPacketIf.HostIfName, reply.SwIfIndex)
+	return reply.SwIfIndex, nil
+}
+
+// DeleteAfPacketInterface calls VPP binary API to remove the given af_packet interface.
+func DeleteAfPacketInterface(afPacketIf *intf.Interfaces_Interface_Afpacket, vppChan *govppapi.Channel) error {
+	if afPacketIf == nil || afPacketIf.HostIfName == "" {
+		return fmt.Errorf("af_packet interface is missing host interface name")
+	}
+
+	req := &af_packet.AfPacketDelete{
+		HostIfName: []byte(afPacketIf.HostIfName),
+	}
+	reply := &af_packet.AfPacketDeleteReply{}
+	if err := vppChan.SendRequest(req).ReceiveReply(reply); err != nil {
+		return err
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("af_packet_delete returned %d", reply.Retval)
+	}
+	return nil
+}
+
+// DumpAfPacketInterfaces returns all af_packet interfaces currently configured in VPP,
+// filtering out stale entries VPP still reports for interfaces that were already
+// deleted (a known af_packet_dump quirk fixed upstream by skipping invalid sw_if_index).
+func DumpAfPacketInterfaces(vppChan *govppapi.Channel) ([]*AfPacketDetails, error) {
+	req := &af_packet.AfPacketDump{}
+	reqCtx := vppChan.SendMultiRequest(req)
+
+	var afPackets []*AfPacketDetails
+	for {
+		msg := &af_packet.AfPacketDetails{}
+		stop, err := reqCtx.ReceiveReply(msg)
+		if stop {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if msg.SwIfIndex == invalidSwIfIndex {
+			// already deleted interface still surfaced by af_packet_dump, skip it
+			continue
+		}
+		afPackets = append(afPackets, &AfPacketDetails{
+			SwIfIndex:  msg.SwIfIndex,
+			HostIfName: string(bytesToString(msg.HostIfName)),
+		})
+	}
+	return afPackets, nil
+}
+
+func bytesToString(b []byte) []byte {
+	n := 0
+	for n < len(b) && b[n] != 0 {
+		n++
+	}
+	return b[:n]
+}
@@ -0,0 +1,41 @@
+package vppcalls
+
+import (
+	"fmt"
+	"net"
+
+	govppapi "git.fd.io/govpp.git/api"
+	"github.com/ligato/vpp-agent/defaultplugins/ifplugin/bin_api/interfaces"
+)
+
+// AddInterfaceIPv4Address calls VPP binary API to assign an IPv4 address to the VPP
+// side of an already-created interface, via sw_interface_add_del_address. Used e.g. to
+// give a TAPv2 interface's VPP side an address once tap_create_v2 has returned its
+// sw_if_index.
+func AddInterfaceIPv4Address(swIfIndex uint32, address string, prefixLen uint32, vppChan *govppapi.Channel) error {
+	ip := net.ParseIP(address).To4()
+	if ip == nil {
+		return fmt.Errorf("invalid IPv4 address %q", address)
+	}
+	if prefixLen == 0 {
+		prefixLen = 32
+	}
+
+	addr := make([]byte, 16)
+	copy(addr, ip)
+
+	req := &interfaces.SwInterfaceAddDelAddress{
+		SwIfIndex:     swIfIndex,
+		IsAdd:         1,
+		AddressLength: uint8(prefixLen),
+		Address:       addr,
+	}
+	reply := &interfaces.SwInterfaceAddDelAddressReply{}
+	if err := vppChan.SendRequest(req).ReceiveReply(reply); err != nil {
+		return err
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("sw_interface_add_del_address returned %d", reply.Retval)
+	}
+	return nil
+}
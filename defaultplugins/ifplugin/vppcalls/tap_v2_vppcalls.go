@@ -0,0 +1,87 @@
+package vppcalls
+
+import (
+	"fmt"
+	"net"
+
+	govppapi "git.fd.io/govpp.git/api"
+	log "github.com/ligato/cn-infra/logging/logrus"
+	"github.com/ligato/vpp-agent/defaultplugins/ifplugin/bin_api/tapv2"
+	intf "github.com/ligato/vpp-agent/defaultplugins/ifplugin/model/interfaces"
+)
+
+// AddTapV2Interface calls VPP binary API to create a TAPv2 interface. Unlike
+// af_packet, VPP creates both ends of the link - the host side is brought up as a
+// side effect of tap_create_v2 rather than having to exist beforehand.
+func AddTapV2Interface(tap *intf.Interfaces_Interface_Tap, vppChan *govppapi.Channel) (swIndex uint32, err error) {
+	if tap == nil || tap.HostIfName == "" {
+		return 0, fmt.Errorf("tapv2 interface is missing host interface name")
+	}
+
+	req := &tapv2.TapCreateV2{
+		HostIfName:  []byte(tap.HostIfName),
+		NumRxQueues: uint16(tap.NumRxQueues),
+		RxRingSz:    uint16(tap.RxRingSize),
+		TxRingSz:    uint16(tap.TxRingSize),
+	}
+	if tap.EnableGso {
+		req.Flags |= uint32(tapv2.TapFlagGso)
+	}
+	if tap.HostMacAddress != "" {
+		hwAddr, err := net.ParseMAC(tap.HostMacAddress)
+		if err != nil {
+			return 0, fmt.Errorf("invalid host MAC address %q: %v", tap.HostMacAddress, err)
+		}
+		req.HostMacAddr = []byte(hwAddr)
+	} else {
+		req.UseRandomMac = 1
+	}
+	if tap.HostIpAddress != "" {
+		ip := net.ParseIP(tap.HostIpAddress).To4()
+		if ip == nil {
+			return 0, fmt.Errorf("invalid host IP address %q", tap.HostIpAddress)
+		}
+		req.HostIP4Addr = []byte(ip)
+		req.HostIP4PrefixLen = hostIP4PrefixLenOrDefault(tap.HostIp4PrefixLen)
+	}
+
+	reply := &tapv2.TapCreateV2Reply{}
+	if err = vppChan.SendRequest(req).ReceiveReply(reply); err != nil {
+		return 0, err
+	}
+	if reply.Retval != 0 {
+		return 0, fmt.Errorf("tap_create_v2 returned %d", reply.Retval)
+	}
+	log.Debugf("TAPv2 interface %s created via tap_create_v2 (swIfIndex: %d)", tap.HostIfName, reply.SwIfIndex)
+
+	if tap.VppIpAddress != "" {
+		if err := AddInterfaceIPv4Address(reply.SwIfIndex, tap.VppIpAddress, tap.VppIp4PrefixLen, vppChan); err != nil {
+			return reply.SwIfIndex, fmt.Errorf("failed to assign VPP-side address to TAPv2 interface %s: %v", tap.HostIfName, err)
+		}
+	}
+	return reply.SwIfIndex, nil
+}
+
+// hostIP4PrefixLenOrDefault returns prefixLen, defaulting to a /32 host route when the
+// caller didn't specify one.
+func hostIP4PrefixLenOrDefault(prefixLen uint32) uint8 {
+	if prefixLen == 0 {
+		return 32
+	}
+	return uint8(prefixLen)
+}
+
+// DeleteTapV2Interface calls VPP binary API to remove a TAPv2 interface.
+func DeleteTapV2Interface(swIndex uint32, vppChan *govppapi.Channel) error {
+	req := &tapv2.TapDeleteV2{
+		SwIfIndex: swIndex,
+	}
+	reply := &tapv2.TapDeleteV2Reply{}
+	if err := vppChan.SendRequest(req).ReceiveReply(reply); err != nil {
+		return err
+	}
+	if reply.Retval != 0 {
+		return fmt.Errorf("tap_delete_v2 returned %d", reply.Retval)
+	}
+	return nil
+}